@@ -0,0 +1,88 @@
+package badgercache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupWithOptionsRoundTrip(t *testing.T) {
+	c, err := NewCache(time.Minute, nil, nil)
+	assert.Nil(t, err)
+	src, err := c.Open("test-cache-backup-opts-src")
+	assert.Nil(t, err)
+	defer src.Close()
+
+	assert.Nil(t, src.Write([]byte("a"), []byte("hello")))
+	assert.Nil(t, src.Write([]byte("b"), []byte("world")))
+
+	var buf bytes.Buffer
+	opts := BackupOptions{
+		Compression: CompressionSnappy,
+		Cipher:      CipherAESGCM,
+		Key:         []byte("a very secret backup key"),
+	}
+	upto, err := src.BackupWithOptions(&buf, 0, opts)
+	assert.Nil(t, err)
+	assert.True(t, upto > 0)
+
+	dst, err := c.Open("test-cache-backup-opts-dst")
+	assert.Nil(t, err)
+	defer dst.Close()
+
+	since, loadedUpto, err := dst.LoadWithOptions(&buf, LoadOptions{Cipher: CipherAESGCM, Key: opts.Key})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), since)
+	assert.Equal(t, upto, loadedUpto)
+
+	v, err := dst.Read([]byte("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("hello"), v)
+}
+
+func TestLoadWithOptionsWrongKeyFails(t *testing.T) {
+	c, err := NewCache(time.Minute, nil, nil)
+	assert.Nil(t, err)
+	src, err := c.Open("test-cache-backup-opts-badkey-src")
+	assert.Nil(t, err)
+	defer src.Close()
+
+	assert.Nil(t, src.Write([]byte("a"), []byte("hello")))
+
+	var buf bytes.Buffer
+	opts := BackupOptions{Cipher: CipherChaCha20Poly1305, Key: []byte("key-one")}
+	_, err = src.BackupWithOptions(&buf, 0, opts)
+	assert.Nil(t, err)
+
+	dst, err := c.Open("test-cache-backup-opts-badkey-dst")
+	assert.Nil(t, err)
+	defer dst.Close()
+
+	_, _, err = dst.LoadWithOptions(&buf, LoadOptions{Cipher: CipherChaCha20Poly1305, Key: []byte("key-two")})
+	assert.NotNil(t, err)
+}
+
+func TestResumeBackup(t *testing.T) {
+	c, err := NewCache(time.Minute, nil, nil)
+	assert.Nil(t, err)
+	src, err := c.Open("test-cache-resume-backup")
+	assert.Nil(t, err)
+	defer src.Close()
+
+	assert.Nil(t, src.Write([]byte("a"), []byte("hello")))
+
+	var buf bytes.Buffer
+	opts := BackupOptions{}
+	state, err := src.ResumeBackup(&buf, BackupState{}, opts)
+	assert.Nil(t, err)
+	assert.True(t, state.Since > 0)
+
+	assert.Nil(t, src.Write([]byte("b"), []byte("world")))
+
+	var buf2 bytes.Buffer
+	state, err = src.ResumeBackup(&buf2, state, opts)
+	assert.Nil(t, err)
+	assert.True(t, state.Since > 0)
+}
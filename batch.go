@@ -0,0 +1,154 @@
+package badgercache
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dgraph-io/badger"
+)
+
+// Entry is a single key/value/TTL tuple for the batch APIs. A zero TTL
+// falls back to the Conn's default TTL; a negative TTL means "no expiry".
+type Entry struct {
+	Key   []byte
+	Value []byte
+	TTL   time.Duration
+}
+
+// Delete removes a key from the cache immediately, ahead of its TTL
+func (c *Conn) Delete(k []byte) error {
+	return c.db.Update(func(txn *badger.Txn) error {
+		if err := c.untrackLRU(txn, k); err != nil {
+			return err
+		}
+		return txn.Delete(k)
+	})
+}
+
+// WriteBatch writes multiple entries using a single Badger write batch,
+// which is far cheaper than one transaction per key for bulk ingest or
+// cache warming.
+func (c *Conn) WriteBatch(entries []Entry) error {
+	wb := c.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	for _, e := range entries {
+		ttl := resolveTTL(e.TTL, c.TTL)
+		if ttl < time.Second && ttl > 0 {
+			return errors.New("TTL must be >= 1 second. Badger uses Unix timestamps for expiries which operate in second resolution")
+		}
+
+		var err error
+		if ttl > 0 {
+			err = wb.SetEntry(badger.NewEntry(e.Key, e.Value).WithTTL(ttl))
+		} else {
+			err = wb.Set(e.Key, e.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := wb.Flush(); err != nil {
+		return err
+	}
+	return c.trackLRUBatch(entries)
+}
+
+// WriteBatchTTL writes multiple key/value pairs using a single Badger write
+// batch, all with the same explicit ttl (a zero ttl falls back to the
+// Conn's default TTL; a negative ttl means "no expiry").
+func (c *Conn) WriteBatchTTL(entries []Entry, ttl time.Duration) error {
+	withTTL := make([]Entry, len(entries))
+	for i, e := range entries {
+		withTTL[i] = Entry{Key: e.Key, Value: e.Value, TTL: ttl}
+	}
+	return c.WriteBatch(withTTL)
+}
+
+// ReadBatch retrieves multiple keys in a single read transaction. It
+// returns a map of the values that were found, keyed by the string form of
+// each key, and a slice of per-key errors in the same order as keys - a
+// missing key does not fail the whole batch.
+func (c *Conn) ReadBatch(keys [][]byte) (map[string][]byte, []error) {
+	values := make(map[string][]byte, len(keys))
+	errs := make([]error, len(keys))
+
+	c.db.View(func(txn *badger.Txn) error {
+		for i, k := range keys {
+			item, err := txn.Get(k)
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			v, err := itemValue(item)
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			values[string(k)] = v
+		}
+		return nil
+	})
+
+	return values, errs
+}
+
+// DeleteBatch deletes multiple keys using a single Badger write batch.
+func (c *Conn) DeleteBatch(keys [][]byte) error {
+	wb := c.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, k := range keys {
+		if err := wb.Delete(k); err != nil {
+			return err
+		}
+	}
+	if err := wb.Flush(); err != nil {
+		return err
+	}
+	return c.untrackLRUBatch(keys)
+}
+
+// trackLRUBatch and untrackLRUBatch update eviction bookkeeping for entries
+// written/deleted via WriteBatch/DeleteBatch. badger's WriteBatch has no
+// transactional read-then-write hook like trackLRU/untrackLRU need, so this
+// runs as one follow-up transaction after the batch is flushed.
+func (c *Conn) trackLRUBatch(entries []Entry) error {
+	if c.evictMaxBytes <= 0 && c.evictMaxEntries <= 0 {
+		return nil
+	}
+	return c.db.Update(func(txn *badger.Txn) error {
+		for _, e := range entries {
+			if err := c.trackLRU(txn, e.Key, len(e.Value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c *Conn) untrackLRUBatch(keys [][]byte) error {
+	if c.evictMaxBytes <= 0 && c.evictMaxEntries <= 0 {
+		return nil
+	}
+	return c.db.Update(func(txn *badger.Txn) error {
+		for _, k := range keys {
+			if err := c.untrackLRU(txn, k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// resolveTTL applies the batch APIs' TTL convention: zero falls back to
+// def, negative means no expiry, and a positive value is used as-is.
+func resolveTTL(ttl, def time.Duration) time.Duration {
+	switch {
+	case ttl < 0:
+		return 0
+	case ttl == 0:
+		return def
+	default:
+		return ttl
+	}
+}
@@ -0,0 +1,157 @@
+package badgercache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelete(t *testing.T) {
+	c, err := NewCache(0, nil, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-delete")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	key := []byte("del")
+	assert.Nil(t, conn.Write(key, []byte{1}))
+
+	assert.Nil(t, conn.Delete(key))
+	_, err = conn.Read(key)
+	assert.Errorf(t, err, "Key not found")
+}
+
+func TestWriteBatch(t *testing.T) {
+	c, err := NewCache(time.Minute, nil, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-write-batch")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	entries := []Entry{
+		{Key: []byte("a"), Value: []byte{1}},
+		{Key: []byte("b"), Value: []byte{2}, TTL: -1},
+		{Key: []byte("c"), Value: []byte{3}, TTL: time.Second},
+	}
+	assert.Nil(t, conn.WriteBatch(entries))
+
+	v, err := conn.Read([]byte("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1}, v)
+
+	time.Sleep(time.Second)
+	v, err = conn.Read([]byte("b"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{2}, v)
+
+	_, err = conn.Read([]byte("c"))
+	assert.Errorf(t, err, "Key not found")
+}
+
+func TestWriteBatchTTL(t *testing.T) {
+	c, err := NewCache(0, nil, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-write-batch-ttl")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	entries := []Entry{
+		{Key: []byte("a"), Value: []byte{1}},
+		{Key: []byte("b"), Value: []byte{2}},
+	}
+	assert.Nil(t, conn.WriteBatchTTL(entries, time.Second))
+
+	time.Sleep(time.Second)
+	_, err = conn.Read([]byte("a"))
+	assert.Errorf(t, err, "Key not found")
+	_, err = conn.Read([]byte("b"))
+	assert.Errorf(t, err, "Key not found")
+}
+
+func TestReadBatch(t *testing.T) {
+	c, err := NewCache(0, nil, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-read-batch")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	assert.Nil(t, conn.Write([]byte("a"), []byte{1}))
+	assert.Nil(t, conn.Write([]byte("b"), []byte{2}))
+
+	values, errs := conn.ReadBatch([][]byte{[]byte("a"), []byte("missing"), []byte("b")})
+	assert.Nil(t, errs[0])
+	assert.NotNil(t, errs[1])
+	assert.Nil(t, errs[2])
+	assert.Equal(t, []byte{1}, values["a"])
+	assert.Equal(t, []byte{2}, values["b"])
+	_, ok := values["missing"]
+	assert.False(t, ok)
+}
+
+func TestWriteBatchTracksEviction(t *testing.T) {
+	gcOpts := GarbageCollectionOptions{
+		Frequency:    time.Hour,
+		DiscardRatio: 0.5,
+		MaxEntries:   2,
+	}
+	c, err := NewCache(0, nil, &gcOpts)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-write-batch-eviction")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	entries := []Entry{
+		{Key: []byte("a"), Value: []byte{1}},
+		{Key: []byte("b"), Value: []byte{2}},
+		{Key: []byte("c"), Value: []byte{3}},
+	}
+	assert.Nil(t, conn.WriteBatch(entries))
+
+	entryCount, err := conn.countEntries()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), entryCount)
+
+	assert.Nil(t, conn.evict())
+	entryCount, err = conn.countEntries()
+	assert.Nil(t, err)
+	assert.True(t, entryCount <= 2)
+}
+
+func TestDeleteRemovesEvictionBookkeeping(t *testing.T) {
+	gcOpts := GarbageCollectionOptions{
+		Frequency:    time.Hour,
+		DiscardRatio: 0.5,
+		MaxEntries:   10,
+	}
+	c, err := NewCache(0, nil, &gcOpts)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-delete-eviction")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	assert.Nil(t, conn.Write([]byte("a"), []byte{1}))
+	assert.Nil(t, conn.Delete([]byte("a")))
+
+	entryCount, err := conn.countEntries()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), entryCount)
+}
+
+func TestDeleteBatch(t *testing.T) {
+	c, err := NewCache(0, nil, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-delete-batch")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	assert.Nil(t, conn.Write([]byte("a"), []byte{1}))
+	assert.Nil(t, conn.Write([]byte("b"), []byte{2}))
+
+	assert.Nil(t, conn.DeleteBatch([][]byte{[]byte("a"), []byte("b")}))
+
+	_, err = conn.Read([]byte("a"))
+	assert.Errorf(t, err, "Key not found")
+	_, err = conn.Read([]byte("b"))
+	assert.Errorf(t, err, "Key not found")
+}
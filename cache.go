@@ -3,6 +3,7 @@ package badgercache
 import (
 	"errors"
 	"io"
+	"sync/atomic"
 	"time"
 
 	"github.com/dgraph-io/badger"
@@ -21,12 +22,27 @@ type Conn struct {
 	TTL    time.Duration
 	db     *badger.DB
 	ticker *time.Ticker // for GC loop
+
+	lruSeq          *badger.Sequence // monotonic source for the LRU index
+	evictMaxBytes   int64
+	evictMaxEntries int64
+	evictions       uint64 // atomic: keys removed by the eviction loop
 }
 
 // GarbageCollectionOptions specifies settings for Badger garbage collection
+// and, optionally, size-bounded LRU eviction. MaxBytes and MaxEntries are
+// both 0 by default, which leaves the cache unbounded other than by TTL.
 type GarbageCollectionOptions struct {
 	Frequency    time.Duration
 	DiscardRatio float64
+
+	// MaxBytes is the combined LSM+VLog size, in bytes, above which the
+	// least-recently-written keys are evicted. 0 disables size-based
+	// eviction.
+	MaxBytes int64
+	// MaxEntries is the number of keys above which the least-recently-written
+	// keys are evicted. 0 disables entry-count-based eviction.
+	MaxEntries int64
 }
 
 // Stats displays stats about badger
@@ -43,9 +59,6 @@ func NewCache(defaultTimeout time.Duration, opts *badger.Options, gcOpts *Garbag
 	if defaultTimeout < time.Second && defaultTimeout > 0 {
 		return &Cache{}, errors.New("TTL must be >= 1 second. Badger uses Unix timestamps for expiries which operate in second resolution")
 	}
-	if opts == nil {
-		opts = &badger.DefaultOptions
-	}
 	if gcOpts == nil {
 		gcOpts = &DefaultGCOptions
 	}
@@ -55,6 +68,10 @@ func NewCache(defaultTimeout time.Duration, opts *badger.Options, gcOpts *Garbag
 
 // Open opens a new connection to Badger
 func (c Cache) Open(name string) (*Conn, error) {
+	if c.opts == nil {
+		defaultOpts := badger.DefaultOptions(name)
+		c.opts = &defaultOpts
+	}
 	c.opts.Dir = name
 	c.opts.ValueDir = name
 
@@ -62,23 +79,41 @@ func (c Cache) Open(name string) (*Conn, error) {
 	if err != nil {
 		return &Conn{}, err
 	}
-	// start a GC loop
+	seq, err := db.GetSequence([]byte(lruSeqKey), 100)
+	if err != nil {
+		return &Conn{}, err
+	}
+
+	conn := &Conn{
+		TTL:             c.TTL,
+		db:              db,
+		lruSeq:          seq,
+		evictMaxBytes:   c.gcOpts.MaxBytes,
+		evictMaxEntries: c.gcOpts.MaxEntries,
+	}
+
+	// start a GC + eviction loop
 	ticker := time.NewTicker(c.gcOpts.Frequency)
-	go func(t *time.Ticker, d *badger.DB) {
+	go func(t *time.Ticker, conn *Conn) {
 		for range t.C {
 		again:
-			err := d.RunValueLogGC(c.gcOpts.DiscardRatio)
+			err := conn.db.RunValueLogGC(c.gcOpts.DiscardRatio)
 			if err == nil {
 				goto again
 			}
+			conn.evict()
 		}
-	}(ticker, db)
-	return &Conn{TTL: c.TTL, ticker: ticker, db: db}, nil
+	}(ticker, conn)
+	conn.ticker = ticker
+	return conn, nil
 }
 
 // Close closes the badger connection
 func (c *Conn) Close() error {
 	c.ticker.Stop()
+	if c.lruSeq != nil {
+		c.lruSeq.Release()
+	}
 	return c.db.Close()
 }
 
@@ -91,7 +126,10 @@ func (c *Conn) Write(k, v []byte) error {
 // a TTL of 0 does not expire keys
 func (c *Conn) WriteTTL(k, v []byte, ttl time.Duration) error {
 	return c.db.Update(func(txn *badger.Txn) error {
-		return setWithTTL(txn, k, v, ttl)
+		if err := setWithTTL(txn, k, v, ttl); err != nil {
+			return err
+		}
+		return c.trackLRU(txn, k, len(v))
 	})
 }
 
@@ -103,18 +141,66 @@ func (c *Conn) Read(k []byte) ([]byte, error) {
 		if err != nil {
 			return err
 		}
-		ret, err = item.Value()
+		ret, err = itemValue(item)
 		return err
 	})
 	return ret, err
 }
 
-// Stats provides stats about the Badger database
-func (c *Conn) Stats() map[string]interface{} {
+// RemainingTTL returns the time remaining until k expires, or 0 if k has no
+// expiry set. It returns an error if k does not exist. Badger only stores
+// expiries with second resolution, so the result is rounded up to the next
+// whole second (floored at one second) rather than truncated - otherwise a
+// key read back just before a second boundary would report under a second
+// of remaining TTL and trip setWithTTL's one-second floor.
+func (c *Conn) RemainingTTL(k []byte) (time.Duration, error) {
+	var ttl time.Duration
+	err := c.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(k)
+		if err != nil {
+			return err
+		}
+		if expiresAt := item.ExpiresAt(); expiresAt > 0 {
+			remaining := time.Until(time.Unix(int64(expiresAt), 0))
+			if whole := remaining.Truncate(time.Second); whole < remaining {
+				remaining = whole + time.Second
+			}
+			if remaining < time.Second {
+				remaining = time.Second
+			}
+			ttl = remaining
+		}
+		return nil
+	})
+	return ttl, err
+}
+
+// CountPrefix returns the number of keys in the cache whose key begins with
+// prefix. It scans keys only, not values.
+func (c *Conn) CountPrefix(prefix []byte) (int64, error) {
+	var n int64
+	err := c.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Stats provides stats about the Badger database, including the number of
+// keys removed so far by LRU eviction (see GarbageCollectionOptions)
+func (c *Conn) Stats() Stats {
 	lsm, vlog := c.db.Size()
 	return Stats{
-		"LSMSize":  lsm,
-		"VLogSize": vlog,
+		"LSMSize":   lsm,
+		"VLogSize":  vlog,
+		"Evictions": atomic.LoadUint64(&c.evictions),
 	}
 }
 
@@ -136,7 +222,7 @@ func (c *Conn) Backup(w io.Writer, since uint64) (upto uint64, err error) {
 // DB.Load() should be called on a database that is not running any other
 // concurrent transactions while it is running.
 func (c *Conn) Load(r io.Reader) error {
-	return c.db.Load(r)
+	return c.db.Load(r, loadMaxPendingWrites)
 }
 
 func setWithTTL(txn *badger.Txn, k, v []byte, ttl time.Duration) error {
@@ -144,7 +230,7 @@ func setWithTTL(txn *badger.Txn, k, v []byte, ttl time.Duration) error {
 	if ttl < time.Second && ttl > 0 {
 		return errors.New("TTL must be >= 1 second. Badger uses Unix timestamps for expiries which operate in second resolution")
 	} else if ttl > 0 {
-		err := txn.SetWithTTL(k, v, ttl)
+		err := txn.SetEntry(badger.NewEntry(k, v).WithTTL(ttl))
 		if err != nil {
 			return err
 		}
@@ -22,12 +22,12 @@ func TestMain(m *testing.M) {
 }
 
 func TestNewCache(t *testing.T) {
-	opts := badger.DefaultOptions
+	opts := badger.DefaultOptions("test-cache-new")
 	c, err := NewCache(time.Second, &opts, &DefaultGCOptions)
 	assert.Nil(t, err)
 
 	assert.Equal(t, time.Second, c.TTL)
-	assert.Equal(t, &badger.DefaultOptions, c.opts)
+	assert.Equal(t, &opts, c.opts)
 	assert.Equal(t, &DefaultGCOptions, c.gcOpts)
 }
 
@@ -146,7 +146,6 @@ func TestStats(t *testing.T) {
 	assert.Nil(t, err)
 	defer conn.Close()
 
-	s, err := conn.Stats()
-	assert.Nil(t, err)
-	assert.Equal(t, map[string]interface{}{"LSMSize": int64(0), "VLogSize": int64(0)}, s)
+	s := conn.Stats()
+	assert.Equal(t, Stats{"LSMSize": int64(0), "VLogSize": int64(0), "Evictions": uint64(0)}, s)
 }
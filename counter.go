@@ -0,0 +1,78 @@
+package badgercache
+
+import (
+	"time"
+
+	"github.com/dgraph-io/badger"
+)
+
+// Increment adds delta to the uint64 counter stored at k, treating a missing
+// key as zero, and returns the new value. The item's existing TTL, if any, is
+// preserved.
+func (c *Conn) Increment(k []byte, delta uint64) (uint64, error) {
+	return c.incrementBy(k, delta, true, false, 0)
+}
+
+// Decrement subtracts delta from the uint64 counter stored at k, treating a
+// missing key as zero, and returns the new value. The item's existing TTL, if
+// any, is preserved. The result is floored at zero.
+func (c *Conn) Decrement(k []byte, delta uint64) (uint64, error) {
+	return c.incrementBy(k, delta, false, false, 0)
+}
+
+// IncrementTTL adds delta to the uint64 counter stored at k, treating a
+// missing key as zero, and returns the new value. The item's expiration is
+// reset to ttl.
+func (c *Conn) IncrementTTL(k []byte, delta uint64, ttl time.Duration) (uint64, error) {
+	return c.incrementBy(k, delta, true, true, ttl)
+}
+
+// DecrementTTL subtracts delta from the uint64 counter stored at k, treating
+// a missing key as zero, and returns the new value. The item's expiration is
+// reset to ttl. The result is floored at zero.
+func (c *Conn) DecrementTTL(k []byte, delta uint64, ttl time.Duration) (uint64, error) {
+	return c.incrementBy(k, delta, false, true, ttl)
+}
+
+func (c *Conn) incrementBy(k []byte, delta uint64, up bool, resetTTL bool, ttl time.Duration) (uint64, error) {
+	var result uint64
+	err := c.db.Update(func(txn *badger.Txn) error {
+		var current uint64
+		var existingTTL time.Duration
+
+		item, err := txn.Get(k)
+		switch err {
+		case nil:
+			v, err := itemValue(item)
+			if err != nil {
+				return err
+			}
+			current = bytesToUint64(v)
+			if expiresAt := item.ExpiresAt(); expiresAt > 0 {
+				existingTTL = time.Until(time.Unix(int64(expiresAt), 0))
+			}
+		case badger.ErrKeyNotFound:
+			current = 0
+		default:
+			return err
+		}
+
+		if up {
+			result = current + delta
+		} else if delta > current {
+			result = 0
+		} else {
+			result = current - delta
+		}
+
+		useTTL := existingTTL
+		if resetTTL {
+			useTTL = ttl
+		}
+		if err := setWithTTL(txn, k, uint64ToBytes(result), useTTL); err != nil {
+			return err
+		}
+		return c.trackLRU(txn, k, len(uint64ToBytes(result)))
+	})
+	return result, err
+}
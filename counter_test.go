@@ -0,0 +1,119 @@
+package badgercache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncrement(t *testing.T) {
+	c, err := NewCache(time.Second, nil, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-increment")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	key := []byte("counter")
+
+	// missing key treated as zero
+	v, err := conn.Increment(key, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), v)
+
+	v, err = conn.Increment(key, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(8), v)
+}
+
+func TestDecrement(t *testing.T) {
+	c, err := NewCache(time.Second, nil, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-decrement")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	key := []byte("counter")
+
+	_, err = conn.Increment(key, 10)
+	assert.Nil(t, err)
+
+	v, err := conn.Decrement(key, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(6), v)
+
+	// floors at zero instead of underflowing
+	v, err = conn.Decrement(key, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), v)
+}
+
+func TestIncrementTTL(t *testing.T) {
+	c, err := NewCache(0, nil, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-increment-ttl")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	key := []byte("counter")
+
+	v, err := conn.IncrementTTL(key, 1, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), v)
+
+	time.Sleep(time.Second)
+	_, err = conn.Read(key)
+	assert.Errorf(t, err, "Key not found")
+}
+
+func TestIncrementTracksLRU(t *testing.T) {
+	gcOpts := GarbageCollectionOptions{
+		Frequency:    time.Hour,
+		DiscardRatio: 0.5,
+		MaxEntries:   2,
+	}
+	c, err := NewCache(0, nil, &gcOpts)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-increment-lru")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	// counter keys must land in the LRU index just like Write does, or
+	// eviction can never catch up to MaxEntries
+	_, err = conn.Increment([]byte("a"), 1)
+	assert.Nil(t, err)
+	_, err = conn.Increment([]byte("b"), 1)
+	assert.Nil(t, err)
+	_, err = conn.Increment([]byte("c"), 1)
+	assert.Nil(t, err)
+
+	assert.Nil(t, conn.evict())
+
+	entries, err := conn.countEntries()
+	assert.Nil(t, err)
+	assert.True(t, entries <= 2)
+
+	_, err = conn.Read([]byte("a"))
+	assert.Errorf(t, err, "Key not found")
+}
+
+func TestDecrementTTL(t *testing.T) {
+	c, err := NewCache(0, nil, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-decrement-ttl")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	key := []byte("counter")
+
+	_, err = conn.Increment(key, 5)
+	assert.Nil(t, err)
+
+	v, err := conn.DecrementTTL(key, 2, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), v)
+
+	time.Sleep(time.Second)
+	_, err = conn.Read(key)
+	assert.Errorf(t, err, "Key not found")
+}
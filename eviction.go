@@ -0,0 +1,160 @@
+package badgercache
+
+import (
+	"sync/atomic"
+
+	"github.com/dgraph-io/badger"
+)
+
+// Keys under these prefixes are internal LRU bookkeeping, not user data.
+// __meta/lru/<seq>  -> user key, ordered by write recency
+// __meta/size/<key> -> value length (8 bytes) + lru seq (8 bytes)
+const (
+	metaPrefix = "__meta/"
+	lruPrefix  = metaPrefix + "lru/"
+	sizePrefix = metaPrefix + "size/"
+	lruSeqKey  = metaPrefix + "lru-seq"
+)
+
+// trackLRU records k as the most-recently-written key, replacing any prior
+// LRU entry for k. It is a no-op when neither MaxBytes nor MaxEntries is
+// configured. Must be called inside the same transaction that writes k.
+func (c *Conn) trackLRU(txn *badger.Txn, k []byte, size int) error {
+	if c.evictMaxBytes <= 0 && c.evictMaxEntries <= 0 {
+		return nil
+	}
+
+	sizeKey := append([]byte(sizePrefix), k...)
+	if old, err := txn.Get(sizeKey); err == nil {
+		v, err := itemValue(old)
+		if err != nil {
+			return err
+		}
+		oldLRUKey := append([]byte(lruPrefix), v[8:]...)
+		if err := txn.Delete(oldLRUKey); err != nil {
+			return err
+		}
+	} else if err != badger.ErrKeyNotFound {
+		return err
+	}
+
+	seq, err := c.lruSeq.Next()
+	if err != nil {
+		return err
+	}
+	seqBytes := uint64ToBytes(seq)
+
+	meta := append(uint64ToBytes(uint64(size)), seqBytes...)
+	if err := txn.Set(sizeKey, meta); err != nil {
+		return err
+	}
+	return txn.Set(append([]byte(lruPrefix), seqBytes...), k)
+}
+
+// untrackLRU removes the LRU/size bookkeeping for k, if any, so a deleted
+// key doesn't linger as a phantom entry in eviction accounting. It is a
+// no-op when neither MaxBytes nor MaxEntries is configured. Must be called
+// inside the same transaction that deletes k.
+func (c *Conn) untrackLRU(txn *badger.Txn, k []byte) error {
+	if c.evictMaxBytes <= 0 && c.evictMaxEntries <= 0 {
+		return nil
+	}
+
+	sizeKey := append([]byte(sizePrefix), k...)
+	item, err := txn.Get(sizeKey)
+	if err == badger.ErrKeyNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	v, err := itemValue(item)
+	if err != nil {
+		return err
+	}
+	if err := txn.Delete(append([]byte(lruPrefix), v[8:]...)); err != nil {
+		return err
+	}
+	return txn.Delete(sizeKey)
+}
+
+// evict deletes the least-recently-written keys until the database is back
+// under MaxBytes and MaxEntries. It is called once per GC tick.
+func (c *Conn) evict() error {
+	if c.evictMaxBytes <= 0 && c.evictMaxEntries <= 0 {
+		return nil
+	}
+
+	for {
+		over, err := c.overLimit()
+		if err != nil || !over {
+			return err
+		}
+		evicted, err := c.evictOldest()
+		if err != nil || !evicted {
+			return err
+		}
+		atomic.AddUint64(&c.evictions, 1)
+	}
+}
+
+func (c *Conn) overLimit() (bool, error) {
+	if c.evictMaxBytes > 0 {
+		lsm, vlog := c.db.Size()
+		if lsm+vlog > c.evictMaxBytes {
+			return true, nil
+		}
+	}
+	if c.evictMaxEntries > 0 {
+		entries, err := c.countEntries()
+		if err != nil {
+			return false, err
+		}
+		if entries > c.evictMaxEntries {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *Conn) countEntries() (int64, error) {
+	return c.CountPrefix([]byte(lruPrefix))
+}
+
+// evictOldest deletes the single oldest entry in the LRU index, along with
+// its size-tracking entry and the underlying user key. It reports false if
+// the index is empty.
+func (c *Conn) evictOldest() (bool, error) {
+	found := false
+	err := c.db.Update(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		it := txn.NewIterator(opts)
+
+		prefix := []byte(lruPrefix)
+		it.Seek(prefix)
+		if !it.ValidForPrefix(prefix) {
+			it.Close()
+			return nil
+		}
+		item := it.Item()
+		lruKey := append([]byte{}, item.Key()...)
+		userKey, err := itemValue(item)
+		if err != nil {
+			it.Close()
+			return err
+		}
+		it.Close()
+
+		if err := txn.Delete(lruKey); err != nil {
+			return err
+		}
+		if err := txn.Delete(append([]byte(sizePrefix), userKey...)); err != nil {
+			return err
+		}
+		if err := txn.Delete(userKey); err != nil {
+			return err
+		}
+		found = true
+		return nil
+	})
+	return found, err
+}
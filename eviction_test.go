@@ -0,0 +1,53 @@
+package badgercache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvictionMaxEntries(t *testing.T) {
+	gcOpts := GarbageCollectionOptions{
+		Frequency:    time.Hour,
+		DiscardRatio: 0.5,
+		MaxEntries:   2,
+	}
+	c, err := NewCache(0, nil, &gcOpts)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-evict-entries")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	assert.Nil(t, conn.Write([]byte("a"), []byte{1}))
+	assert.Nil(t, conn.Write([]byte("b"), []byte{2}))
+	assert.Nil(t, conn.Write([]byte("c"), []byte{3}))
+
+	assert.Nil(t, conn.evict())
+
+	entries, err := conn.countEntries()
+	assert.Nil(t, err)
+	assert.True(t, entries <= 2)
+
+	_, err = conn.Read([]byte("a"))
+	assert.Errorf(t, err, "Key not found")
+
+	s := conn.Stats()
+	assert.Equal(t, uint64(1), s["Evictions"])
+}
+
+func TestEvictionDisabledByDefault(t *testing.T) {
+	opts := badger.DefaultOptions("test-cache-evict-disabled")
+	c, err := NewCache(0, &opts, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-cache-evict-disabled")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	assert.Nil(t, conn.Write([]byte("a"), []byte{1}))
+	assert.Nil(t, conn.evict())
+
+	_, err = conn.Read([]byte("a"))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,42 @@
+package badgercache
+
+import (
+	"encoding/binary"
+
+	"github.com/dgraph-io/badger"
+)
+
+// loadMaxPendingWrites bounds how many writes DB.Load buffers before
+// blocking on a commit, matching badger's own recommended default.
+const loadMaxPendingWrites = 256
+
+// itemValue copies out item's value using Badger's callback-based Value
+// API, so callers can keep working with plain byte slices.
+func itemValue(item *badger.Item) ([]byte, error) {
+	var v []byte
+	err := item.Value(func(val []byte) error {
+		v = append([]byte{}, val...)
+		return nil
+	})
+	return v, err
+}
+
+// uint64ToBytes encodes a uint64 as big-endian bytes, suitable for storing
+// as a Badger value
+func uint64ToBytes(i uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, i)
+	return b
+}
+
+// bytesToUint64 decodes big-endian bytes produced by uint64ToBytes back into
+// a uint64. Missing or undersized values are treated as zero by callers.
+func bytesToUint64(b []byte) uint64 {
+	return binary.BigEndian.Uint64(b)
+}
+
+// add sums two big-endian encoded uint64 values and returns the result
+// encoded the same way
+func add(a, b []byte) []byte {
+	return uint64ToBytes(bytesToUint64(a) + bytesToUint64(b))
+}
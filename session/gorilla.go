@@ -0,0 +1,106 @@
+package session
+
+import (
+	"encoding/base32"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	gorillasessions "github.com/gorilla/sessions"
+)
+
+// GorillaStore adapts a BadgerStore to gorilla/sessions' Store interface, so
+// an http.Handler using gorilla/sessions can be pointed at the cache with no
+// other code changes.
+type GorillaStore struct {
+	store   *BadgerStore
+	Codecs  []securecookie.Codec
+	Options *gorillasessions.Options
+}
+
+// NewGorillaStore creates a GorillaStore backed by store. keyPairs are used
+// to authenticate/encrypt the session cookie, as in
+// securecookie.CodecsFromPairs. Following gorilla/sessions' own convention,
+// the default Options.MaxAge is 0 when store's ttl is 0, meaning the cookie
+// carries no Max-Age attribute (a browser-session cookie) rather than being
+// destroyed on Save.
+func NewGorillaStore(store *BadgerStore, keyPairs ...[]byte) *GorillaStore {
+	return &GorillaStore{
+		store:  store,
+		Codecs: securecookie.CodecsFromPairs(keyPairs...),
+		Options: &gorillasessions.Options{
+			Path:   "/",
+			MaxAge: int(store.ttl / time.Second),
+		},
+	}
+}
+
+// Get returns the named session, registering it with gorilla's per-request
+// registry so repeated calls within a request return the same instance.
+func (g *GorillaStore) Get(r *http.Request, name string) (*gorillasessions.Session, error) {
+	return gorillasessions.GetRegistry(r).Get(g, name)
+}
+
+// New creates a session for name, populating it from the request's cookie
+// when one is present and its signature verifies.
+func (g *GorillaStore) New(r *http.Request, name string) (*gorillasessions.Session, error) {
+	session := gorillasessions.NewSession(g, name)
+	opts := *g.Options
+	session.Options = &opts
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+	if err := securecookie.DecodeMulti(name, cookie.Value, &session.ID, g.Codecs...); err != nil {
+		return session, nil
+	}
+
+	data, err := g.store.Get(session.ID)
+	if err != nil {
+		return session, nil
+	}
+	for k, v := range data {
+		session.Values[k] = v
+	}
+	session.IsNew = false
+	return session, nil
+}
+
+// Save persists session to the cache and sets the signed cookie that
+// identifies it. Per gorilla/sessions convention, a negative MaxAge destroys
+// the session and expires the cookie; MaxAge == 0 means the cookie has no
+// Max-Age attribute (and the session is persisted using the store's default
+// ttl, i.e. no idle timeout when that ttl is also 0).
+func (g *GorillaStore) Save(r *http.Request, w http.ResponseWriter, session *gorillasessions.Session) error {
+	if session.ID == "" {
+		session.ID = strings.TrimRight(base32.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(32)), "=")
+	}
+
+	if session.Options.MaxAge < 0 {
+		if err := g.store.Destroy(session.ID); err != nil {
+			return err
+		}
+		http.SetCookie(w, gorillasessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	data := make(map[string]interface{}, len(session.Values))
+	for k, v := range session.Values {
+		if ks, ok := k.(string); ok {
+			data[ks] = v
+		}
+	}
+	if err := g.store.Set(session.ID, data, time.Duration(session.Options.MaxAge)*time.Second); err != nil {
+		return err
+	}
+
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, g.Codecs...)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, gorillasessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
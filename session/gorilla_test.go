@@ -0,0 +1,72 @@
+package session
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	badgercache "github.com/panoplymedia/omni-cache-badger"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGorillaStore(t *testing.T, dir string, ttl time.Duration) *GorillaStore {
+	c, err := badgercache.NewCache(time.Minute, nil, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open(dir)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	store := New(conn, "", ttl)
+	return NewGorillaStore(store, []byte("test-secret-key-32-bytes-long!!"))
+}
+
+func TestGorillaStoreSaveAndGetZeroTTL(t *testing.T) {
+	// ttl == 0 means "no idle timeout", per BadgerStore/Cache convention, so
+	// Options.MaxAge is also 0 -- Save must persist the session rather than
+	// treating MaxAge == 0 as "destroy"
+	g := newTestGorillaStore(t, "test-gorilla-zero-ttl", 0)
+	assert.Equal(t, 0, g.Options.MaxAge)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	session, err := g.New(r, "session")
+	assert.Nil(t, err)
+	session.Values["user_id"] = 42
+
+	w := httptest.NewRecorder()
+	assert.Nil(t, g.Save(r, w, session))
+
+	cookies := w.Result().Cookies()
+	assert.Equal(t, 1, len(cookies))
+
+	r2 := httptest.NewRequest("GET", "/", nil)
+	r2.AddCookie(cookies[0])
+	got, err := g.New(r2, "session")
+	assert.Nil(t, err)
+	assert.False(t, got.IsNew)
+	assert.Equal(t, 42, got.Values["user_id"])
+}
+
+func TestGorillaStoreSaveNegativeMaxAgeDestroys(t *testing.T) {
+	g := newTestGorillaStore(t, "test-gorilla-negative-maxage", time.Minute)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	session, err := g.New(r, "session")
+	assert.Nil(t, err)
+	session.Values["user_id"] = 42
+
+	w := httptest.NewRecorder()
+	assert.Nil(t, g.Save(r, w, session))
+	cookies := w.Result().Cookies()
+
+	session.Options.MaxAge = -1
+	w2 := httptest.NewRecorder()
+	assert.Nil(t, g.Save(r, w2, session))
+
+	r2 := httptest.NewRequest("GET", "/", nil)
+	r2.AddCookie(cookies[0])
+	got, err := g.New(r2, "session")
+	assert.Nil(t, err)
+	assert.True(t, got.IsNew)
+
+	_, err = g.store.Get(session.ID)
+	assert.Equal(t, ErrNotFound, err)
+}
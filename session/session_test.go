@@ -0,0 +1,80 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	badgercache "github.com/panoplymedia/omni-cache-badger"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(t *testing.T, dir string) *BadgerStore {
+	c, err := badgercache.NewCache(time.Minute, nil, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open(dir)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return New(conn, "", time.Minute)
+}
+
+func TestSetGet(t *testing.T) {
+	s := newTestStore(t, "test-session-set-get")
+
+	data := map[string]interface{}{"user_id": 42}
+	assert.Nil(t, s.Set("abc", data, 0))
+
+	got, err := s.Get("abc")
+	assert.Nil(t, err)
+	assert.Equal(t, data["user_id"], got["user_id"])
+	assert.Equal(t, 1, s.All())
+}
+
+func TestDestroy(t *testing.T) {
+	s := newTestStore(t, "test-session-destroy")
+
+	assert.Nil(t, s.Set("abc", map[string]interface{}{"a": 1}, 0))
+	assert.Nil(t, s.Destroy("abc"))
+	assert.Equal(t, 0, s.All())
+
+	_, err := s.Get("abc")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestRegeneratePreservesCustomTTL(t *testing.T) {
+	c, err := badgercache.NewCache(time.Hour, nil, nil)
+	assert.Nil(t, err)
+	conn, err := c.Open("test-session-regenerate-ttl")
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	s := New(conn, "", time.Hour)
+
+	// a per-session ttl much shorter than the store's default must survive
+	// Regenerate, not silently widen back out to the default - checked via
+	// RemainingTTL rather than a sleep/expiry race, since Badger's
+	// second-resolution expiries make a short sleep flaky
+	assert.Nil(t, s.Set("old", map[string]interface{}{"a": 1}, time.Minute))
+	assert.Nil(t, s.Regenerate("old", "new"))
+
+	got, err := s.Get("new")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, got["a"])
+
+	ttl, err := conn.RemainingTTL(s.key("new"))
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0)
+	assert.True(t, ttl <= time.Minute)
+}
+
+func TestRegenerate(t *testing.T) {
+	s := newTestStore(t, "test-session-regenerate")
+
+	assert.Nil(t, s.Set("old", map[string]interface{}{"a": 1}, 0))
+	assert.Nil(t, s.Regenerate("old", "new"))
+
+	got, err := s.Get("new")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, got["a"])
+
+	_, err = s.Get("old")
+	assert.Equal(t, ErrNotFound, err)
+}